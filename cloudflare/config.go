@@ -0,0 +1,37 @@
+package cloudflare
+
+import (
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/pkg/errors"
+)
+
+// Config contains cloudflare provider schema data.
+type Config struct {
+	Email    string
+	Token    string
+	APIToken string
+	Options  []cloudflare.Option
+}
+
+// Client returns a new client for accessing Cloudflare.
+//
+// If APIToken is set it takes precedence and the client is built from
+// cloudflare.NewWithAPIToken, which does not require an Email. Otherwise we
+// fall back to the legacy Email + Global API Key pair.
+func (c *Config) Client() (*cloudflare.API, error) {
+	if c.APIToken != "" {
+		client, err := cloudflare.NewWithAPIToken(c.APIToken, c.Options...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating Cloudflare client")
+		}
+
+		return client, nil
+	}
+
+	client, err := cloudflare.New(c.Token, c.Email, c.Options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Cloudflare client")
+	}
+
+	return client, nil
+}
@@ -0,0 +1,61 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCloudflareR2Bucket() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCloudflareR2BucketRead,
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"location": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"storage_class": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCloudflareR2BucketRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+	name := d.Get("name").(string)
+
+	bucket, err := client.GetR2Bucket(ctx, accountID, name)
+	if err != nil {
+		return diag.Errorf("error reading r2 bucket %q: %s", name, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", accountID, name))
+	d.Set("location", bucket.Location)
+	d.Set("storage_class", bucket.StorageClass)
+	d.Set("endpoint", cloudflareR2BucketEndpoint(accountID, name))
+
+	return nil
+}
@@ -1,36 +1,45 @@
 package cloudflare
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/cloudflare/cloudflare-go"
+	cloudflare "github.com/cloudflare/cloudflare-go"
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/terraform/helper/logging"
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/httpclient"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/httpclient"
 	"github.com/terraform-providers/terraform-provider-cloudflare/version"
 )
 
-// Provider returns a terraform.ResourceProvider.
-func Provider() terraform.ResourceProvider {
+// Provider returns a *schema.Provider.
+func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"email": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CLOUDFLARE_EMAIL", nil),
-				Description: "A registered Cloudflare email address.",
+				Description: "A registered Cloudflare email address. Required unless `api_token` is set.",
 			},
 
 			"token": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CLOUDFLARE_TOKEN", nil),
-				Description: "The token key for API operations.",
+				Description: "The Global API Key for API operations. Required unless `api_token` is set.",
+			},
+
+			"api_token": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDFLARE_API_TOKEN", nil),
+				Description: "The scoped API token for API operations. Cannot be used alongside `email`/`token`.",
 			},
 
 			"rps": &schema.Schema{
@@ -81,47 +90,102 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("CLOUDFLARE_ORG_ID", nil),
 				Description: "Configure API client to always use that organization. If set this will override 'user_owner_from_zone'",
 			},
+
+			"rate_limits": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Per-endpoint rate limit overrides. A request matching a 'path_prefix' is limited by that entry instead of the global 'rps' limit; the most specific matching 'path_prefix' wins.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path_prefix": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "API path prefix this limit applies to, e.g. '/zones/' + zone ID + '/dns_records'.",
+						},
+						"rps": {
+							Type:        schema.TypeFloat,
+							Required:    true,
+							Description: "Requests per second permitted to this path prefix.",
+						},
+						"burst": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1,
+							Description: "Maximum burst size for this path prefix's token bucket.",
+						},
+					},
+				},
+			},
+
+			"retry_on_status": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "HTTP status codes that trigger a retry. Defaults to 429, 500, 502, 503, 504.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"cloudflare_ip_ranges": dataSourceCloudflareIPRanges(),
+			"cloudflare_r2_bucket": dataSourceCloudflareR2Bucket(),
 			"cloudflare_zones":     dataSourceCloudflareZones(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"cloudflare_access_application":     resourceCloudflareAccessApplication(),
-			"cloudflare_access_policy":          resourceCloudflareAccessPolicy(),
-			"cloudflare_access_rule":            resourceCloudflareAccessRule(),
-			"cloudflare_account_member":         resourceCloudflareAccountMember(),
-			"cloudflare_argo":                   resourceCloudflareArgo(),
-			"cloudflare_custom_pages":           resourceCloudflareCustomPages(),
-			"cloudflare_custom_ssl":             resourceCloudflareCustomSsl(),
-			"cloudflare_filter":                 resourceCloudflareFilter(),
-			"cloudflare_firewall_rule":          resourceCloudflareFirewallRule(),
-			"cloudflare_load_balancer_monitor":  resourceCloudflareLoadBalancerMonitor(),
-			"cloudflare_load_balancer_pool":     resourceCloudflareLoadBalancerPool(),
-			"cloudflare_load_balancer":          resourceCloudflareLoadBalancer(),
-			"cloudflare_logpush_job":            resourceCloudflareLogpushJob(),
-			"cloudflare_page_rule":              resourceCloudflarePageRule(),
-			"cloudflare_rate_limit":             resourceCloudflareRateLimit(),
-			"cloudflare_record":                 resourceCloudflareRecord(),
-			"cloudflare_spectrum_application":   resourceCloudflareSpectrumApplication(),
-			"cloudflare_waf_rule":               resourceCloudflareWAFRule(),
-			"cloudflare_worker_route":           resourceCloudflareWorkerRoute(),
-			"cloudflare_worker_script":          resourceCloudflareWorkerScript(),
-			"cloudflare_zone_lockdown":          resourceCloudflareZoneLockdown(),
-			"cloudflare_zone_settings_override": resourceCloudflareZoneSettingsOverride(),
-			"cloudflare_zone":                   resourceCloudflareZone(),
+			"cloudflare_access_application":          resourceCloudflareAccessApplication(),
+			"cloudflare_access_identity_provider":    resourceCloudflareAccessIdentityProvider(),
+			"cloudflare_access_policy":               resourceCloudflareAccessPolicy(),
+			"cloudflare_access_rule":                 resourceCloudflareAccessRule(),
+			"cloudflare_access_service_token":        resourceCloudflareAccessServiceToken(),
+			"cloudflare_account_member":              resourceCloudflareAccountMember(),
+			"cloudflare_api_token":                   resourceCloudflareApiToken(),
+			"cloudflare_argo":                        resourceCloudflareArgo(),
+			"cloudflare_custom_pages":                resourceCloudflareCustomPages(),
+			"cloudflare_custom_ssl":                  resourceCloudflareCustomSsl(),
+			"cloudflare_dnslink_tree":                resourceCloudflareDnslinkTree(),
+			"cloudflare_filter":                      resourceCloudflareFilter(),
+			"cloudflare_firewall_rule":               resourceCloudflareFirewallRule(),
+			"cloudflare_load_balancer_monitor":       resourceCloudflareLoadBalancerMonitor(),
+			"cloudflare_load_balancer_pool":          resourceCloudflareLoadBalancerPool(),
+			"cloudflare_load_balancer":               resourceCloudflareLoadBalancer(),
+			"cloudflare_logpush_job":                 resourceCloudflareLogpushJob(),
+			"cloudflare_logpush_ownership_challenge": resourceCloudflareLogpushOwnershipChallenge(),
+			"cloudflare_page_rule":                   resourceCloudflarePageRule(),
+			"cloudflare_r2_bucket":                   resourceCloudflareR2Bucket(),
+			"cloudflare_rate_limit":                  resourceCloudflareRateLimit(),
+			"cloudflare_record":                      resourceCloudflareRecord(),
+			"cloudflare_ruleset":                     resourceCloudflareRuleset(),
+			"cloudflare_spectrum_application":        resourceCloudflareSpectrumApplication(),
+			"cloudflare_waf_rule":                    resourceCloudflareWAFRule(),
+			"cloudflare_worker_route":                resourceCloudflareWorkerRoute(),
+			"cloudflare_worker_script":               resourceCloudflareWorkerScript(),
+			"cloudflare_zone_lockdown":               resourceCloudflareZoneLockdown(),
+			"cloudflare_zone_settings_override":      resourceCloudflareZoneSettingsOverride(),
+			"cloudflare_zone":                        resourceCloudflareZone(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	limitOpt := cloudflare.UsingRateLimit(float64(d.Get("rps").(int)))
-	retryOpt := cloudflare.UsingRetryPolicy(d.Get("retries").(int), d.Get("min_backoff").(int), d.Get("max_backoff").(int))
-	options := []cloudflare.Option{limitOpt, retryOpt}
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	email := d.Get("email").(string)
+	token := d.Get("token").(string)
+	apiToken := d.Get("api_token").(string)
+
+	if apiToken != "" && (email != "" || token != "") {
+		return nil, diag.Errorf("'api_token' is mutually exclusive with 'email' and 'token'")
+	}
+	if apiToken == "" && (email == "" || token == "") {
+		return nil, diag.Errorf("either 'api_token' or both 'email' and 'token' must be set")
+	}
+
+	// Rate limiting and retries are both handled by rateLimitedRetryTransport
+	// below, which also lets individual resources override the retry count
+	// via their "retry" block. cloudflare.UsingRateLimit/UsingRetryPolicy are
+	// deliberately not used here too, since stacking them would rate-limit
+	// and retry every request twice over.
+	var options []cloudflare.Option
 
 	if d.Get("api_client_logging").(bool) {
 		options = append(options, cloudflare.UsingLogger(log.New(os.Stderr, "", log.LstdFlags)))
@@ -129,17 +193,26 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	c := cleanhttp.DefaultClient()
 	c.Transport = logging.NewTransport("Cloudflare", c.Transport)
+	c.Transport = &rateLimitedRetryTransport{
+		underlying:    c.Transport,
+		limiter:       newSharedRateLimiter(expandCloudflareRateLimits(d.Get("rate_limits").([]interface{})), float64(d.Get("rps").(int))),
+		retryOnStatus: expandCloudflareRetryOnStatus(d.Get("retry_on_status").([]interface{})),
+		retryMax:      d.Get("retries").(int),
+		minBackoff:    time.Duration(d.Get("min_backoff").(int)) * time.Second,
+		maxBackoff:    time.Duration(d.Get("max_backoff").(int)) * time.Second,
+	}
 	options = append(options, cloudflare.HTTPClient(c))
 
 	config := Config{
-		Email:   d.Get("email").(string),
-		Token:   d.Get("token").(string),
-		Options: options,
+		Email:    email,
+		Token:    token,
+		APIToken: apiToken,
+		Options:  options,
 	}
 
 	client, err := config.Client()
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
 
 	if orgId, ok := d.GetOk("org_id"); ok {
@@ -148,18 +221,18 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	} else if zoneName, ok := d.GetOk("use_org_from_zone"); ok {
 		zoneId, err := client.ZoneIDByName(zoneName.(string))
 		if err != nil {
-			return nil, fmt.Errorf("error finding zone %q: %s", zoneName.(string), err)
+			return nil, diag.Errorf("error finding zone %q: %s", zoneName.(string), err)
 		}
 
-		zone, err := client.ZoneDetails(zoneId)
+		zone, err := client.ZoneDetails(ctx, zoneId)
 		if err != nil {
-			return nil, err
+			return nil, diag.FromErr(err)
 		}
 		log.Printf("[DEBUG] Looked up zone to match organization details to: %#v", zone)
 
-		orgs, _, err := client.ListOrganizations()
+		orgs, _, err := client.ListOrganizations(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error listing organizations: %s", err.Error())
+			return nil, diag.Errorf("error listing organizations: %s", err.Error())
 		}
 		log.Printf("[DEBUG] Found organizations for current user: %#v", orgs)
 
@@ -175,26 +248,26 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 			log.Printf("[INFO] Zone ownership specified but organization owner not found. Falling back to using user API for Cloudflare provider")
 		}
 	} else {
-		return client, err
+		return client, nil
 	}
 
-	// TODO: This is the SDK version not the CLI version, once we are on 0.12, should revisit
-	tfUserAgent := httpclient.UserAgentString()
+	tfUserAgent := httpclient.TerraformUserAgent(httpclient.TerraformVersion)
 
 	pv := version.ProviderVersion
 	providerUserAgent := fmt.Sprintf("%s terraform-provider-cloudflare/%s", tfUserAgent, pv)
 	options = append(options, cloudflare.UserAgent(strings.TrimSpace(fmt.Sprintf("%s %s", client.UserAgent, providerUserAgent))))
 
 	config = Config{
-		Email:   d.Get("email").(string),
-		Token:   d.Get("token").(string),
-		Options: options,
+		Email:    email,
+		Token:    token,
+		APIToken: apiToken,
+		Options:  options,
 	}
 
 	client, err = config.Client()
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
 
-	return client, err
+	return client, nil
 }
@@ -0,0 +1,271 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type retryMaxContextKey struct{}
+
+// contextWithResourceRetryMax threads a resource's "retry.retry_max"
+// override onto the context passed into cloudflare-go calls, so the
+// shared rateLimitedRetryTransport can honor it for that request without
+// resources needing their own *http.Client.
+func contextWithResourceRetryMax(ctx context.Context, d *schema.ResourceData) context.Context {
+	retry, ok := d.GetOk("retry")
+	if !ok {
+		return ctx
+	}
+
+	blocks := retry.([]interface{})
+	if len(blocks) != 1 || blocks[0] == nil {
+		return ctx
+	}
+
+	retryMax := blocks[0].(map[string]interface{})["retry_max"].(int)
+
+	return context.WithValue(ctx, retryMaxContextKey{}, retryMax)
+}
+
+// resourceRetrySchema returns the reusable `retry` nested block that
+// resources embed to override the provider-wide retry count for their own
+// requests, e.g. a resource that does bulk DNS record operations against a
+// much tighter per-endpoint Cloudflare limit.
+func resourceRetrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"retry_max": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     0,
+					Description: "Overrides the provider-wide 'retries' count for this resource's own requests. 0 defers to the provider default.",
+				},
+			},
+		},
+	}
+}
+
+// pathRateLimit is one entry of the provider's `rate_limits` block: a
+// token bucket scoped to requests whose path starts with PathPrefix.
+type pathRateLimit struct {
+	PathPrefix string
+	RPS        float64
+	Burst      int
+}
+
+// tokenBucket is a simple, goroutine-safe token bucket. Terraform's graph
+// walker runs resource CRUD concurrently, so every goroutine sharing a
+// *cloudflare.API must coordinate through the same bucket instance rather
+// than each keeping its own local limiter.
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	max        float64
+	rps        float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		tokens:     float64(burst),
+		max:        float64(burst),
+		rps:        rps,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available.
+func (b *tokenBucket) Wait() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.lastRefill).Seconds() * b.rps
+		if b.tokens > b.max {
+			b.tokens = b.max
+		}
+		b.lastRefill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.rps * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// sharedRateLimiter dispatches requests to the most specific matching
+// pathRateLimit bucket, falling back to the provider-wide default.
+type sharedRateLimiter struct {
+	buckets []struct {
+		prefix string
+		bucket *tokenBucket
+	}
+	defaultBucket *tokenBucket
+}
+
+func newSharedRateLimiter(limits []pathRateLimit, defaultRPS float64) *sharedRateLimiter {
+	sl := &sharedRateLimiter{defaultBucket: newTokenBucket(defaultRPS, int(defaultRPS)+1)}
+
+	for _, l := range limits {
+		sl.buckets = append(sl.buckets, struct {
+			prefix string
+			bucket *tokenBucket
+		}{prefix: l.PathPrefix, bucket: newTokenBucket(l.RPS, l.Burst)})
+	}
+
+	return sl
+}
+
+func (sl *sharedRateLimiter) bucketFor(path string) *tokenBucket {
+	best := sl.defaultBucket
+	bestLen := -1
+	for _, b := range sl.buckets {
+		if len(b.prefix) > bestLen && hasPathPrefix(path, b.prefix) {
+			best = b.bucket
+			bestLen = len(b.prefix)
+		}
+	}
+
+	return best
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return len(path) >= len(prefix) && path[:len(prefix)] == prefix
+}
+
+// rateLimitedRetryTransport wraps an underlying http.RoundTripper, applying
+// the shared per-prefix token buckets and retrying on the configured status
+// codes. A Retry-After header takes priority; otherwise it backs off
+// exponentially between minBackoff and maxBackoff.
+type rateLimitedRetryTransport struct {
+	underlying    http.RoundTripper
+	limiter       *sharedRateLimiter
+	retryOnStatus map[int]bool
+	retryMax      int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+}
+
+func (t *rateLimitedRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bucket := t.limiter.bucketFor(req.URL.Path)
+
+	retryMax := t.retryMax
+	if override, ok := req.Context().Value(retryMaxContextKey{}).(int); ok && override > 0 {
+		retryMax = override
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= retryMax; attempt++ {
+		// A retried request must not resend the body already drained by the
+		// previous attempt. req.GetBody (set by http.NewRequest for common
+		// body types) gives us a fresh reader to rewind it with.
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry request to %s: body does not support rewinding", req.URL)
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			req.Body = body
+		}
+
+		bucket.Wait()
+
+		resp, err = t.underlying.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+
+		if !t.retryOnStatus[resp.StatusCode] || attempt == retryMax {
+			return resp, nil
+		}
+
+		wait := retryAfterDuration(resp.Header.Get("Retry-After"), attempt, t.minBackoff, t.maxBackoff)
+		resp.Body.Close()
+		time.Sleep(wait)
+	}
+
+	return resp, err
+}
+
+// retryAfterDuration honors a Retry-After header when present; otherwise it
+// backs off exponentially from minBackoff, doubling per attempt and capped
+// at maxBackoff.
+func retryAfterDuration(header string, attempt int, minBackoff, maxBackoff time.Duration) time.Duration {
+	if header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if when, err := http.ParseTime(header); err == nil {
+			return time.Until(when)
+		}
+	}
+
+	if minBackoff <= 0 {
+		minBackoff = time.Second
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	wait := minBackoff << uint(attempt)
+	if wait <= 0 || wait > maxBackoff {
+		wait = maxBackoff
+	}
+
+	return wait
+}
+
+var defaultRetryOnStatus = []int{429, 500, 502, 503, 504}
+
+func expandCloudflareRateLimits(in []interface{}) []pathRateLimit {
+	limits := make([]pathRateLimit, 0, len(in))
+	for _, raw := range in {
+		m := raw.(map[string]interface{})
+		limits = append(limits, pathRateLimit{
+			PathPrefix: m["path_prefix"].(string),
+			RPS:        m["rps"].(float64),
+			Burst:      m["burst"].(int),
+		})
+	}
+
+	return limits
+}
+
+func expandCloudflareRetryOnStatus(in []interface{}) map[int]bool {
+	statuses := defaultRetryOnStatus
+	if len(in) > 0 {
+		statuses = make([]int, 0, len(in))
+		for _, raw := range in {
+			statuses = append(statuses, raw.(int))
+		}
+	}
+
+	out := make(map[int]bool, len(statuses))
+	for _, s := range statuses {
+		out[s] = true
+	}
+
+	return out
+}
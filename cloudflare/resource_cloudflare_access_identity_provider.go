@@ -0,0 +1,230 @@
+package cloudflare
+
+import (
+	"context"
+	"log"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudflareAccessIdentityProvider() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareAccessIdentityProviderCreate,
+		ReadContext:   resourceCloudflareAccessIdentityProviderRead,
+		UpdateContext: resourceCloudflareAccessIdentityProviderUpdate,
+		DeleteContext: resourceCloudflareAccessIdentityProviderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"retry": resourceRetrySchema(),
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"azureAD", "google", "google-apps", "github", "okta", "onelogin",
+					"saml", "onetimepin", "linkedin", "facebook", "oidc", "yandex", "centrify",
+				}, false),
+			},
+
+			"config": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"client_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"client_secret": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"auth_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"token_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"certs_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"directory_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"sso_target_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"idp_public_cert": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"issuer_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"attributes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"email_attribute_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceCloudflareAccessIdentityProviderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	idp := cloudflare.AccessIdentityProvider{
+		Name:   d.Get("name").(string),
+		Type:   d.Get("type").(string),
+		Config: expandCloudflareAccessIdentityProviderConfig(d.Get("config").([]interface{})),
+	}
+
+	result, err := client.CreateAccessIdentityProvider(ctx, accountID, idp)
+	if err != nil {
+		return diag.Errorf("error creating access identity provider: %s", err)
+	}
+
+	d.SetId(result.ID)
+
+	return resourceCloudflareAccessIdentityProviderRead(ctx, d, meta)
+}
+
+func resourceCloudflareAccessIdentityProviderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	idp, err := client.AccessIdentityProvider(ctx, accountID, d.Id())
+	if err != nil {
+		log.Printf("[WARN] access identity provider %s no longer exists: %s", d.Id(), err)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", idp.Name)
+	d.Set("type", idp.Type)
+
+	// The API never returns client_secret, so preserve whatever is already
+	// configured rather than clobbering it with the empty string on every
+	// refresh.
+	clientSecret := ""
+	if config, ok := d.GetOk("config"); ok {
+		if list := config.([]interface{}); len(list) == 1 && list[0] != nil {
+			clientSecret = list[0].(map[string]interface{})["client_secret"].(string)
+		}
+	}
+
+	if err := d.Set("config", flattenCloudflareAccessIdentityProviderConfig(idp.Config, clientSecret)); err != nil {
+		return diag.Errorf("error setting config: %s", err)
+	}
+
+	return nil
+}
+
+func resourceCloudflareAccessIdentityProviderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	idp := cloudflare.AccessIdentityProvider{
+		ID:     d.Id(),
+		Name:   d.Get("name").(string),
+		Type:   d.Get("type").(string),
+		Config: expandCloudflareAccessIdentityProviderConfig(d.Get("config").([]interface{})),
+	}
+
+	if _, err := client.UpdateAccessIdentityProvider(ctx, accountID, d.Id(), idp); err != nil {
+		return diag.Errorf("error updating access identity provider %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudflareAccessIdentityProviderRead(ctx, d, meta)
+}
+
+func resourceCloudflareAccessIdentityProviderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	if _, err := client.DeleteAccessIdentityProvider(ctx, accountID, d.Id()); err != nil {
+		return diag.Errorf("error deleting access identity provider %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func expandCloudflareAccessIdentityProviderConfig(in []interface{}) cloudflare.AccessIdentityProviderConfiguration {
+	if len(in) != 1 || in[0] == nil {
+		return cloudflare.AccessIdentityProviderConfiguration{}
+	}
+
+	m := in[0].(map[string]interface{})
+
+	attributes := make([]string, 0)
+	for _, a := range m["attributes"].([]interface{}) {
+		attributes = append(attributes, a.(string))
+	}
+
+	return cloudflare.AccessIdentityProviderConfiguration{
+		ClientID:           m["client_id"].(string),
+		ClientSecret:       m["client_secret"].(string),
+		AuthURL:            m["auth_url"].(string),
+		TokenURL:           m["token_url"].(string),
+		CertsURL:           m["certs_url"].(string),
+		DirectoryID:        m["directory_id"].(string),
+		SSOTargetURL:       m["sso_target_url"].(string),
+		IdpPublicCert:      m["idp_public_cert"].(string),
+		IssuerURL:          m["issuer_url"].(string),
+		Attributes:         attributes,
+		EmailAttributeName: m["email_attribute_name"].(string),
+	}
+}
+
+func flattenCloudflareAccessIdentityProviderConfig(config cloudflare.AccessIdentityProviderConfiguration, clientSecret string) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"client_id":            config.ClientID,
+		"client_secret":        clientSecret,
+		"auth_url":             config.AuthURL,
+		"token_url":            config.TokenURL,
+		"certs_url":            config.CertsURL,
+		"directory_id":         config.DirectoryID,
+		"sso_target_url":       config.SSOTargetURL,
+		"idp_public_cert":      config.IdpPublicCert,
+		"issuer_url":           config.IssuerURL,
+		"attributes":           config.Attributes,
+		"email_attribute_name": config.EmailAttributeName,
+	}}
+}
@@ -0,0 +1,145 @@
+package cloudflare
+
+import (
+	"context"
+	"log"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudflareAccessServiceToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareAccessServiceTokenCreate,
+		ReadContext:   resourceCloudflareAccessServiceTokenRead,
+		UpdateContext: resourceCloudflareAccessServiceTokenUpdate,
+		DeleteContext: resourceCloudflareAccessServiceTokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"retry": resourceRetrySchema(),
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			// min_days_for_renewal is not sent to the API; when the token's
+			// expiry is within this many days of "now" at plan time, the
+			// provider forces a rotation by recreating the token.
+			"min_days_for_renewal": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+
+			"client_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"client_secret": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudflareAccessServiceTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	token, err := client.CreateAccessServiceToken(ctx, accountID, d.Get("name").(string))
+	if err != nil {
+		return diag.Errorf("error creating access service token: %s", err)
+	}
+
+	d.SetId(token.ID)
+	d.Set("client_id", token.ClientID)
+	d.Set("client_secret", token.ClientSecret)
+
+	return resourceCloudflareAccessServiceTokenRead(ctx, d, meta)
+}
+
+func resourceCloudflareAccessServiceTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	tokens, _, err := client.AccessServiceTokens(ctx, accountID)
+	if err != nil {
+		return diag.Errorf("error reading access service tokens: %s", err)
+	}
+
+	var found *cloudflare.AccessServiceToken
+	for i := range tokens {
+		if tokens[i].ID == d.Id() {
+			found = &tokens[i]
+			break
+		}
+	}
+
+	if found == nil {
+		log.Printf("[WARN] access service token %s no longer exists", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", found.Name)
+	d.Set("client_id", found.ClientID)
+	if found.ExpiresAt != nil {
+		d.Set("expires_at", found.ExpiresAt.Format(time.RFC3339))
+
+		if minDays := d.Get("min_days_for_renewal").(int); minDays > 0 {
+			if time.Until(*found.ExpiresAt) <= time.Duration(minDays)*24*time.Hour {
+				log.Printf("[INFO] access service token %s is within its renewal window, forcing recreation", d.Id())
+				d.SetId("")
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceCloudflareAccessServiceTokenUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	if _, err := client.UpdateAccessServiceToken(ctx, accountID, d.Id(), d.Get("name").(string)); err != nil {
+		return diag.Errorf("error updating access service token %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudflareAccessServiceTokenRead(ctx, d, meta)
+}
+
+func resourceCloudflareAccessServiceTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+
+	if _, err := client.DeleteAccessServiceToken(ctx, accountID, d.Id()); err != nil {
+		return diag.Errorf("error deleting access service token %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
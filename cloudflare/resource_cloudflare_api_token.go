@@ -0,0 +1,189 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudflareApiToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareApiTokenCreate,
+		ReadContext:   resourceCloudflareApiTokenRead,
+		UpdateContext: resourceCloudflareApiTokenUpdate,
+		DeleteContext: resourceCloudflareApiTokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"retry": resourceRetrySchema(),
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"policy": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"effect": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "allow",
+						},
+						"permission_groups": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"resources": {
+							Type:     schema.TypeMap,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			// value is only returned by the API at creation time. A
+			// terraform import of an existing token leaves it permanently
+			// empty, since there is no API call that can recover it.
+			"value": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			// access_key_id/secret_access_key let this token be wired
+			// directly into an S3-compatible provider (e.g. aws_s3_object)
+			// against the R2 endpoint without a separate credential step.
+			// access_key_id is just the token ID, so it's recovered on
+			// import like any other attribute; secret_access_key is
+			// derived from value and shares its import limitation above.
+			"access_key_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"secret_access_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceCloudflareApiTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+
+	token := cloudflare.APIToken{
+		Name:     d.Get("name").(string),
+		Policies: expandCloudflareApiTokenPolicies(d.Get("policy").([]interface{})),
+	}
+
+	result, err := client.CreateAPIToken(ctx, token)
+	if err != nil {
+		return diag.Errorf("error creating api token: %s", err)
+	}
+
+	d.SetId(result.ID)
+	d.Set("value", result.Value)
+	d.Set("access_key_id", r2AccessKeyID(result.ID))
+	d.Set("secret_access_key", r2SecretAccessKey(result.Value))
+
+	return resourceCloudflareApiTokenRead(ctx, d, meta)
+}
+
+func resourceCloudflareApiTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+
+	token, err := client.GetAPIToken(ctx, d.Id())
+	if err != nil {
+		log.Printf("[WARN] api token %s no longer exists: %s", d.Id(), err)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", token.Name)
+	d.Set("access_key_id", r2AccessKeyID(d.Id()))
+
+	return nil
+}
+
+func resourceCloudflareApiTokenUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+
+	token := cloudflare.APIToken{
+		ID:       d.Id(),
+		Name:     d.Get("name").(string),
+		Policies: expandCloudflareApiTokenPolicies(d.Get("policy").([]interface{})),
+	}
+
+	if _, err := client.UpdateAPIToken(ctx, d.Id(), token); err != nil {
+		return diag.Errorf("error updating api token %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudflareApiTokenRead(ctx, d, meta)
+}
+
+func resourceCloudflareApiTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+
+	if err := client.DeleteAPIToken(ctx, d.Id()); err != nil {
+		return diag.Errorf("error deleting api token %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func expandCloudflareApiTokenPolicies(in []interface{}) []cloudflare.APITokenPolicies {
+	policies := make([]cloudflare.APITokenPolicies, 0, len(in))
+	for _, raw := range in {
+		m := raw.(map[string]interface{})
+
+		permissionGroups := make([]string, 0)
+		for _, pg := range m["permission_groups"].([]interface{}) {
+			permissionGroups = append(permissionGroups, pg.(string))
+		}
+
+		resources := make(map[string]interface{})
+		for k, v := range m["resources"].(map[string]interface{}) {
+			resources[k] = v
+		}
+
+		policies = append(policies, cloudflare.APITokenPolicies{
+			Effect:           m["effect"].(string),
+			PermissionGroups: permissionGroups,
+			Resources:        resources,
+		})
+	}
+
+	return policies
+}
+
+// r2AccessKeyID and r2SecretAccessKey derive S3-compatible credentials from
+// an API token per Cloudflare's R2 documentation: the access key id is the
+// token id itself, and the secret access key is the SHA-256 digest of the
+// token value.
+func r2AccessKeyID(tokenID string) string {
+	return tokenID
+}
+
+func r2SecretAccessKey(tokenValue string) string {
+	sum := sha256.Sum256([]byte(tokenValue))
+	return hex.EncodeToString(sum[:])
+}
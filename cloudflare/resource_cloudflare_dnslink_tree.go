@@ -0,0 +1,456 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/sha3"
+)
+
+// dnslinkTreeLabelLen is the subdomain label length used for tree nodes,
+// per the EIP-1459 ENR tree scheme: the first 26 characters of the
+// unpadded base32 encoding of the node hash.
+const dnslinkTreeLabelLen = 26
+
+// dnslinkBranchPrefix/dnslinkRootPrefix are the DNS TXT record content
+// prefixes defined by EIP-1459 for branch and root nodes respectively.
+const (
+	dnslinkBranchPrefix = "enrtree-branch:"
+	dnslinkRootPrefix   = "enrtree-root:v1"
+)
+
+// dnslinkBranchFanout bounds how many child labels a single branch node may
+// list. Each child label is dnslinkTreeLabelLen characters plus a separator,
+// so a full branch record, with its enrtree-branch: prefix, is at most 365
+// bytes; that is longer than one 255-byte TXT string. Branches with more
+// children than this fan out over multiple levels instead of listing every
+// child in one record.
+const dnslinkBranchFanout = 13
+
+// dnslinkNode is a single TXT record managed by this resource: a leaf, a
+// branch, or the root itself.
+type dnslinkNode struct {
+	label   string
+	content string
+}
+
+func resourceCloudflareDnslinkTree() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareDnslinkTreeCreate,
+		ReadContext:   resourceCloudflareDnslinkTreeRead,
+		UpdateContext: resourceCloudflareDnslinkTreeUpdate,
+		DeleteContext: resourceCloudflareDnslinkTreeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"retry": resourceRetrySchema(),
+
+			// signing_key is the PEM-encoded secp256k1 private key used to
+			// sign the tree's root record. The PEM body is the raw 32-byte
+			// private key scalar, matching the key format produced by
+			// go-ethereum's crypto.ECDSA helpers.
+			"signing_key": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			// entries are opaque, base64url-encoded leaf strings (e.g.
+			// Ethereum ENRs). Each becomes a leaf TXT record in the entry
+			// tree referenced by the root record's e= value.
+			"entries": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			// links are opaque leaf strings pointing at other ENR trees
+			// (e.g. "enrtree://<pubkey>@<domain>"). Each becomes a leaf TXT
+			// record in the separate link tree referenced by the root
+			// record's l= value, so consumers can walk links and entries
+			// independently.
+			"links": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"sequence_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"enr_root": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"link_root": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"root_record": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			// managed_labels tracks every non-root subdomain label this
+			// resource has published, so a later update can delete labels
+			// that drop out of the entry set instead of leaving them to rot.
+			"managed_labels": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceCloudflareDnslinkTreeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+	domain := d.Get("domain").(string)
+
+	entryLeaves, entryBranch := buildDnslinkLeafTree(expandDnslinkEntries(d.Get("entries").([]interface{})))
+	linkLeaves, linkBranch := buildDnslinkLeafTree(expandDnslinkEntries(d.Get("links").([]interface{})))
+
+	seq := 1
+	rootRecord, err := signDnslinkRoot(d.Get("signing_key").(string), entryBranch.label, linkBranch.label, seq)
+	if err != nil {
+		return diag.Errorf("error signing dnslink root record for %q: %s", domain, err)
+	}
+
+	nodes := dedupeDnslinkNodes(append(append(append([]dnslinkNode{}, entryLeaves...), entryBranch), append(linkLeaves, linkBranch)...))
+	for _, node := range nodes {
+		if _, err := client.CreateDNSRecord(ctx, zoneID, cloudflare.DNSRecord{
+			Type:    "TXT",
+			Name:    node.label + "." + domain,
+			Content: node.content,
+		}); err != nil {
+			return diag.Errorf("error creating dnslink record %q: %s", node.label+"."+domain, err)
+		}
+	}
+	if _, err := client.CreateDNSRecord(ctx, zoneID, cloudflare.DNSRecord{
+		Type:    "TXT",
+		Name:    domain,
+		Content: rootRecord,
+	}); err != nil {
+		return diag.Errorf("error creating dnslink root record for %q: %s", domain, err)
+	}
+
+	d.SetId(zoneID + "/" + domain)
+	d.Set("sequence_number", seq)
+	d.Set("enr_root", entryBranch.label)
+	d.Set("link_root", linkBranch.label)
+	d.Set("root_record", rootRecord)
+	d.Set("managed_labels", dnslinkNodeLabels(nodes))
+
+	return nil
+}
+
+func resourceCloudflareDnslinkTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+	domain := d.Get("domain").(string)
+
+	records, err := client.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Type: "TXT", Name: domain})
+	if err != nil {
+		return diag.Errorf("error reading dnslink root record for %q: %s", domain, err)
+	}
+
+	// The apex may carry other TXT records (SPF, domain verification, ...);
+	// only the one starting with the enrtree-root prefix is ours.
+	var rootRecord string
+	found := false
+	for _, record := range records {
+		if strings.HasPrefix(record.Content, dnslinkRootPrefix) {
+			rootRecord = record.Content
+			found = true
+			break
+		}
+	}
+	if !found {
+		log.Printf("[WARN] dnslink root record for %q no longer exists", domain)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("root_record", rootRecord)
+
+	return nil
+}
+
+func resourceCloudflareDnslinkTreeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+	domain := d.Get("domain").(string)
+
+	oldEntriesRaw, newEntriesRaw := d.GetChange("entries")
+	oldLinksRaw, newLinksRaw := d.GetChange("links")
+	setChanged := !reflect.DeepEqual(oldEntriesRaw, newEntriesRaw) || !reflect.DeepEqual(oldLinksRaw, newLinksRaw)
+
+	entryLeaves, entryBranch := buildDnslinkLeafTree(expandDnslinkEntries(newEntriesRaw.([]interface{})))
+	linkLeaves, linkBranch := buildDnslinkLeafTree(expandDnslinkEntries(newLinksRaw.([]interface{})))
+	nodes := dedupeDnslinkNodes(append(append(append([]dnslinkNode{}, entryLeaves...), entryBranch), append(linkLeaves, linkBranch)...))
+
+	seq := d.Get("sequence_number").(int)
+	if setChanged {
+		seq++
+	}
+
+	rootRecord, err := signDnslinkRoot(d.Get("signing_key").(string), entryBranch.label, linkBranch.label, seq)
+	if err != nil {
+		return diag.Errorf("error signing dnslink root record for %q: %s", domain, err)
+	}
+
+	newLabels := dnslinkNodeLabels(nodes)
+	oldLabels := expandDnslinkEntries(d.Get("managed_labels").([]interface{}))
+
+	for _, label := range oldLabels {
+		if contains(newLabels, label) {
+			continue
+		}
+		if err := deleteDnslinkRecord(ctx, client, zoneID, label+"."+domain, ""); err != nil {
+			return diag.Errorf("error deleting stale dnslink record %q: %s", label+"."+domain, err)
+		}
+	}
+
+	for _, node := range nodes {
+		if err := upsertDnslinkRecord(ctx, client, zoneID, node.label+"."+domain, node.content, ""); err != nil {
+			return diag.Errorf("error reconciling dnslink record %q: %s", node.label+"."+domain, err)
+		}
+	}
+	if err := upsertDnslinkRecord(ctx, client, zoneID, domain, rootRecord, dnslinkRootPrefix); err != nil {
+		return diag.Errorf("error updating dnslink root record for %q: %s", domain, err)
+	}
+
+	d.Set("sequence_number", seq)
+	d.Set("enr_root", entryBranch.label)
+	d.Set("link_root", linkBranch.label)
+	d.Set("root_record", rootRecord)
+	d.Set("managed_labels", newLabels)
+
+	return nil
+}
+
+func resourceCloudflareDnslinkTreeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+	domain := d.Get("domain").(string)
+
+	if err := deleteDnslinkRecord(ctx, client, zoneID, domain, dnslinkRootPrefix); err != nil {
+		return diag.Errorf("error deleting dnslink root record for %q: %s", domain, err)
+	}
+
+	for _, label := range expandDnslinkEntries(d.Get("managed_labels").([]interface{})) {
+		if err := deleteDnslinkRecord(ctx, client, zoneID, label+"."+domain, ""); err != nil {
+			return diag.Errorf("error deleting dnslink record %q: %s", label+"."+domain, err)
+		}
+	}
+
+	return nil
+}
+
+func expandDnslinkEntries(in []interface{}) []string {
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		out = append(out, v.(string))
+	}
+	return out
+}
+
+func dnslinkNodeLabels(nodes []dnslinkNode) []string {
+	labels := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		labels = append(labels, n.label)
+	}
+	return labels
+}
+
+// dedupeDnslinkNodes drops later nodes that share a label with one already
+// seen. The entry and link trees hash identical content to the same label
+// when both are empty (or otherwise coincide), and reconciling the same
+// <label>.<domain> TXT record twice would create it, then immediately
+// "update" it again.
+func dedupeDnslinkNodes(nodes []dnslinkNode) []dnslinkNode {
+	seen := make(map[string]bool, len(nodes))
+	out := make([]dnslinkNode, 0, len(nodes))
+	for _, n := range nodes {
+		if seen[n.label] {
+			continue
+		}
+		seen[n.label] = true
+		out = append(out, n)
+	}
+
+	return out
+}
+
+// dnslinkLabel derives the subdomain label for a node's content: the first
+// dnslinkTreeLabelLen characters of the unpadded base32 encoding of its
+// SHA3-256 hash.
+func dnslinkLabel(content string) string {
+	sum := sha3.Sum256([]byte(content))
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:])
+	if len(encoded) > dnslinkTreeLabelLen {
+		encoded = encoded[:dnslinkTreeLabelLen]
+	}
+	return encoded
+}
+
+// buildDnslinkLeafTree builds the leaf nodes for a set of opaque strings
+// (either the entry tree's entries or the link tree's links), then folds
+// their labels into a Merkle tree of branch nodes via
+// buildDnslinkMerkleTree, returning every node below the root alongside the
+// root branch itself.
+func buildDnslinkLeafTree(values []string) ([]dnslinkNode, dnslinkNode) {
+	leaves := make([]dnslinkNode, 0, len(values))
+	childLabels := make([]string, 0, len(values))
+	for _, v := range values {
+		leaf := dnslinkNode{label: dnslinkLabel(v), content: v}
+		leaves = append(leaves, leaf)
+		childLabels = append(childLabels, leaf.label)
+	}
+
+	branches, root := buildDnslinkMerkleTree(childLabels)
+
+	return append(leaves, branches...), root
+}
+
+// buildDnslinkMerkleTree folds a list of child labels into a tree of branch
+// nodes no wider than dnslinkBranchFanout, recursing level by level until a
+// single root branch remains. It returns every branch below the root, plus
+// the root branch itself. An empty input still yields one (empty) root
+// branch, matching the link tree of a dnslink_tree with no links.
+func buildDnslinkMerkleTree(childLabels []string) ([]dnslinkNode, dnslinkNode) {
+	var nodes []dnslinkNode
+
+	level := childLabels
+	for {
+		var nextLevel []string
+		for _, group := range chunkStrings(level, dnslinkBranchFanout) {
+			content := dnslinkBranchPrefix + strings.Join(group, ",")
+			branch := dnslinkNode{label: dnslinkLabel(content), content: content}
+			nodes = append(nodes, branch)
+			nextLevel = append(nextLevel, branch.label)
+		}
+
+		level = nextLevel
+		if len(level) <= 1 {
+			break
+		}
+	}
+
+	root := nodes[len(nodes)-1]
+
+	return nodes[:len(nodes)-1], root
+}
+
+// chunkStrings splits in into consecutive groups of at most size elements.
+// A nil/empty in yields a single empty group, so callers always get at
+// least one branch node back.
+func chunkStrings(in []string, size int) [][]string {
+	if len(in) == 0 {
+		return [][]string{nil}
+	}
+
+	var out [][]string
+	for i := 0; i < len(in); i += size {
+		end := i + size
+		if end > len(in) {
+			end = len(in)
+		}
+		out = append(out, in[i:end])
+	}
+
+	return out
+}
+
+// signDnslinkRoot builds and signs the root record content in the form
+// "enrtree-root:v1 e=<enr-root> l=<link-root> seq=<n> sig=<base64 sig>".
+func signDnslinkRoot(pemKey, enrRoot, linkRoot string, seq int) (string, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return "", fmt.Errorf("signing_key is not valid PEM")
+	}
+
+	key, err := crypto.ToECDSA(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("signing_key is not a valid secp256k1 private key: %w", err)
+	}
+
+	unsigned := fmt.Sprintf("%s e=%s l=%s seq=%d", dnslinkRootPrefix, enrRoot, linkRoot, seq)
+	digest := sha3.Sum256([]byte(unsigned))
+
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		return "", fmt.Errorf("error signing root record: %w", err)
+	}
+
+	return fmt.Sprintf("%s sig=%s", unsigned, base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// upsertDnslinkRecord and deleteDnslinkRecord only touch TXT records at name
+// whose content starts with prefix; an empty prefix matches every record.
+func upsertDnslinkRecord(ctx context.Context, client *cloudflare.API, zoneID, name, content, prefix string) error {
+	existing, err := client.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Type: "TXT", Name: name})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range existing {
+		if strings.HasPrefix(record.Content, prefix) {
+			return client.UpdateDNSRecord(ctx, zoneID, record.ID, cloudflare.DNSRecord{Type: "TXT", Name: name, Content: content})
+		}
+	}
+
+	_, err = client.CreateDNSRecord(ctx, zoneID, cloudflare.DNSRecord{Type: "TXT", Name: name, Content: content})
+	return err
+}
+
+func deleteDnslinkRecord(ctx context.Context, client *cloudflare.API, zoneID, name, prefix string) error {
+	existing, err := client.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Type: "TXT", Name: name})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range existing {
+		if !strings.HasPrefix(record.Content, prefix) {
+			continue
+		}
+		if err := client.DeleteDNSRecord(ctx, zoneID, record.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudflareLogpushOwnershipChallenge() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareLogpushOwnershipChallengeCreate,
+		ReadContext:   resourceCloudflareLogpushOwnershipChallengeRead,
+		DeleteContext: resourceCloudflareLogpushOwnershipChallengeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"account_id"},
+			},
+
+			"account_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"zone_id"},
+			},
+
+			"destination_conf": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"filename": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"valid_challenge_token": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudflareLogpushOwnershipChallengeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cloudflare.API)
+	destinationConf := d.Get("destination_conf").(string)
+	zoneID := d.Get("zone_id").(string)
+	accountID := d.Get("account_id").(string)
+
+	var challenge cloudflare.LogpushGetOwnershipChallengeResponse
+	var err error
+	if zoneID != "" {
+		challenge, err = client.GetLogpushOwnershipChallenge(ctx, zoneID, destinationConf)
+	} else if accountID != "" {
+		challenge, err = client.GetLogpushOwnershipChallengeAccount(ctx, accountID, destinationConf)
+	} else {
+		return diag.Errorf("one of zone_id or account_id must be set")
+	}
+	if err != nil {
+		return diag.Errorf("error requesting logpush ownership challenge: %s", err)
+	}
+
+	sum := sha256.Sum256([]byte(destinationConf))
+	d.SetId(hex.EncodeToString(sum[:]))
+	d.Set("filename", challenge.Result.Filename)
+	d.Set("valid_challenge_token", challenge.Result.OwnershipChallenge)
+
+	return nil
+}
+
+func resourceCloudflareLogpushOwnershipChallengeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The ownership challenge itself isn't a persisted Cloudflare object —
+	// it's regenerated on demand from destination_conf — so there is
+	// nothing further to reconcile on refresh.
+	return nil
+}
+
+func resourceCloudflareLogpushOwnershipChallengeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,129 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCloudflareR2Bucket() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareR2BucketCreate,
+		ReadContext:   resourceCloudflareR2BucketRead,
+		DeleteContext: resourceCloudflareR2BucketDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudflareR2BucketImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"retry": resourceRetrySchema(),
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ENAM", "WNAM", "EEUR", "WEUR", "APAC"}, false),
+			},
+
+			"storage_class": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "Standard",
+				ValidateFunc: validation.StringInSlice([]string{"Standard", "InfrequentAccess"}, false),
+			},
+
+			"endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func cloudflareR2BucketEndpoint(accountID, name string) string {
+	return fmt.Sprintf("https://%s.r2.cloudflarestorage.com/%s", accountID, name)
+}
+
+func resourceCloudflareR2BucketCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+	name := d.Get("name").(string)
+
+	_, err := client.CreateR2Bucket(ctx, accountID, cloudflare.CreateR2BucketParameters{
+		Name:         name,
+		LocationHint: d.Get("location").(string),
+		StorageClass: d.Get("storage_class").(string),
+	})
+	if err != nil {
+		return diag.Errorf("error creating r2 bucket %q: %s", name, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", accountID, name))
+
+	return resourceCloudflareR2BucketRead(ctx, d, meta)
+}
+
+func resourceCloudflareR2BucketRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+	name := d.Get("name").(string)
+
+	bucket, err := client.GetR2Bucket(ctx, accountID, name)
+	if err != nil {
+		log.Printf("[WARN] r2 bucket %q no longer exists: %s", name, err)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("location", bucket.Location)
+	d.Set("storage_class", bucket.StorageClass)
+	d.Set("endpoint", cloudflareR2BucketEndpoint(accountID, name))
+
+	return nil
+}
+
+func resourceCloudflareR2BucketDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	accountID := d.Get("account_id").(string)
+	name := d.Get("name").(string)
+
+	if err := client.DeleteR2Bucket(ctx, accountID, name); err != nil {
+		return diag.Errorf("error deleting r2 bucket %q: %s", name, err)
+	}
+
+	return nil
+}
+
+func resourceCloudflareR2BucketImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid id %q, expected format accountID/bucketName", d.Id())
+	}
+
+	d.Set("account_id", parts[0])
+	d.Set("name", parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
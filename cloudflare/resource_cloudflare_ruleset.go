@@ -0,0 +1,503 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rulesetRuleRefKey prefixes the keys of the ref lookup map built by
+// expandCloudflareRulesetRulesPreservingIDs. That map holds refs only;
+// expressions are looked up through a separate map.
+const rulesetRuleRefKey = "ref:"
+
+func resourceCloudflareRuleset() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudflareRulesetCreate,
+		ReadContext:   resourceCloudflareRulesetRead,
+		UpdateContext: resourceCloudflareRulesetUpdate,
+		DeleteContext: resourceCloudflareRulesetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudflareRulesetImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"account_id"},
+			},
+
+			"account_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"zone_id"},
+			},
+
+			"retry": resourceRetrySchema(),
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"kind": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"root", "zone", "custom", "managed"}, false),
+			},
+
+			"phase": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"http_request_firewall_custom",
+					"http_request_firewall_managed",
+					"http_ratelimit",
+					"http_request_transform",
+					"http_request_late_transform",
+					"http_request_sbfm",
+					"http_response_firewall_managed",
+				}, false),
+			},
+
+			"rules": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ref": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"expression": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"logging": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"enabled": {
+										Type:     schema.TypeBool,
+										Required: true,
+									},
+								},
+							},
+						},
+						"action_parameters": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"overrides": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"uri": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"headers": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"matched_data": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"ratelimit": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"characteristics": {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"period": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"requests_per_period": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"mitigation_timeout": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceCloudflareRulesetScope(d *schema.ResourceData) (string, string) {
+	zoneID := d.Get("zone_id").(string)
+	accountID := d.Get("account_id").(string)
+	return zoneID, accountID
+}
+
+func resourceCloudflareRulesetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID, accountID := resourceCloudflareRulesetScope(d)
+
+	ruleset := cloudflare.Ruleset{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Kind:        d.Get("kind").(string),
+		Phase:       d.Get("phase").(string),
+		Rules:       expandCloudflareRulesetRules(d.Get("rules").([]interface{})),
+	}
+
+	var created cloudflare.Ruleset
+	var err error
+	if zoneID != "" {
+		created, err = client.CreateZoneRuleset(ctx, zoneID, ruleset)
+	} else if accountID != "" {
+		created, err = client.CreateAccountRuleset(ctx, accountID, ruleset)
+	} else {
+		return diag.Errorf("one of zone_id or account_id must be set")
+	}
+	if err != nil {
+		return diag.Errorf("error creating ruleset: %s", err)
+	}
+
+	d.SetId(created.ID)
+
+	return resourceCloudflareRulesetRead(ctx, d, meta)
+}
+
+func resourceCloudflareRulesetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID, accountID := resourceCloudflareRulesetScope(d)
+
+	var ruleset cloudflare.Ruleset
+	var err error
+	if zoneID != "" {
+		ruleset, err = client.GetZoneRuleset(ctx, zoneID, d.Id())
+	} else {
+		ruleset, err = client.GetAccountRuleset(ctx, accountID, d.Id())
+	}
+	if err != nil {
+		log.Printf("[WARN] ruleset %s no longer exists: %s", d.Id(), err)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", ruleset.Name)
+	d.Set("description", ruleset.Description)
+	d.Set("kind", ruleset.Kind)
+	d.Set("phase", ruleset.Phase)
+	d.Set("rules", flattenCloudflareRulesetRules(ruleset.Rules))
+
+	return nil
+}
+
+func resourceCloudflareRulesetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID, accountID := resourceCloudflareRulesetScope(d)
+
+	// Preserve the existing rule IDs keyed by their ref/expression so that
+	// an ordering-only diff doesn't recreate rules upstream.
+	oldRulesRaw, newRulesRaw := d.GetChange("rules")
+	rules := expandCloudflareRulesetRulesPreservingIDs(oldRulesRaw.([]interface{}), newRulesRaw.([]interface{}))
+	ruleset := cloudflare.Ruleset{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Kind:        d.Get("kind").(string),
+		Phase:       d.Get("phase").(string),
+		Rules:       rules,
+	}
+
+	var err error
+	if zoneID != "" {
+		_, err = client.UpdateZoneRuleset(ctx, zoneID, d.Id(), ruleset)
+	} else {
+		_, err = client.UpdateAccountRuleset(ctx, accountID, d.Id(), ruleset)
+	}
+	if err != nil {
+		return diag.Errorf("error updating ruleset %s: %s", d.Id(), err)
+	}
+
+	return resourceCloudflareRulesetRead(ctx, d, meta)
+}
+
+func resourceCloudflareRulesetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = contextWithResourceRetryMax(ctx, d)
+	client := meta.(*cloudflare.API)
+	zoneID, accountID := resourceCloudflareRulesetScope(d)
+
+	var err error
+	if zoneID != "" {
+		err = client.DeleteZoneRuleset(ctx, zoneID, d.Id())
+	} else {
+		err = client.DeleteAccountRuleset(ctx, accountID, d.Id())
+	}
+	if err != nil {
+		return diag.Errorf("error deleting ruleset %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceCloudflareRulesetImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// Supports `zoneID/rulesetID` or `account/accountID/rulesetID`.
+	parts := strings.SplitN(d.Id(), "/", 3)
+
+	switch len(parts) {
+	case 2:
+		d.Set("zone_id", parts[0])
+		d.SetId(parts[1])
+	case 3:
+		if parts[0] != "account" {
+			return nil, fmt.Errorf("invalid id %q, expected zoneID/rulesetID or account/accountID/rulesetID", d.Id())
+		}
+		d.Set("account_id", parts[1])
+		d.SetId(parts[2])
+	default:
+		return nil, fmt.Errorf("invalid id %q, expected zoneID/rulesetID or account/accountID/rulesetID", d.Id())
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandCloudflareRulesetRules(in []interface{}) []cloudflare.RulesetRule {
+	rules := make([]cloudflare.RulesetRule, 0, len(in))
+	for _, raw := range in {
+		r := raw.(map[string]interface{})
+
+		enabled := r["enabled"].(bool)
+		rule := cloudflare.RulesetRule{
+			ID:          r["id"].(string),
+			Ref:         r["ref"].(string),
+			Expression:  r["expression"].(string),
+			Action:      r["action"].(string),
+			Description: r["description"].(string),
+			Enabled:     &enabled,
+		}
+
+		if logging, ok := r["logging"].([]interface{}); ok && len(logging) == 1 {
+			rule.Logging = expandCloudflareRulesetLogging(logging[0].(map[string]interface{}))
+		}
+
+		if ap, ok := r["action_parameters"].([]interface{}); ok && len(ap) == 1 {
+			rule.ActionParameters = expandCloudflareRulesetActionParameters(ap[0].(map[string]interface{}))
+		}
+
+		if rl, ok := r["ratelimit"].([]interface{}); ok && len(rl) == 1 {
+			rule.RateLimit = expandCloudflareRulesetRateLimit(rl[0].(map[string]interface{}))
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// expandCloudflareRulesetRulesPreservingIDs expands the configured rules,
+// then fills in the server-assigned ID of any rule that doesn't have one
+// yet by matching it against oldRules on ref, falling back to expression.
+// Without this, reordering or inserting a rule would otherwise send an
+// empty ID for every entry and recreate the whole rule set upstream.
+//
+// The expression fallback only applies when an expression is unique among
+// oldRules; two old rules sharing an expression (e.g. a duplicated rule)
+// make the fallback ambiguous, so it's dropped and those rules fall back
+// to the API creating a fresh rule instead of risking the wrong ID.
+func expandCloudflareRulesetRulesPreservingIDs(oldRules, newRules []interface{}) []cloudflare.RulesetRule {
+	ids := make(map[string]string, len(oldRules))
+	exprIDs := make(map[string]string, len(oldRules))
+	ambiguousExprs := make(map[string]bool)
+	for _, raw := range oldRules {
+		r := raw.(map[string]interface{})
+
+		id := r["id"].(string)
+		if id == "" {
+			continue
+		}
+
+		if ref := r["ref"].(string); ref != "" {
+			ids[rulesetRuleRefKey+ref] = id
+		}
+
+		expr := r["expression"].(string)
+		if ambiguousExprs[expr] {
+			continue
+		}
+		if _, seen := exprIDs[expr]; seen {
+			delete(exprIDs, expr)
+			ambiguousExprs[expr] = true
+			continue
+		}
+		exprIDs[expr] = id
+	}
+
+	rules := expandCloudflareRulesetRules(newRules)
+	for i, raw := range newRules {
+		if rules[i].ID != "" {
+			continue
+		}
+
+		r := raw.(map[string]interface{})
+		if ref := r["ref"].(string); ref != "" {
+			if id, ok := ids[rulesetRuleRefKey+ref]; ok {
+				rules[i].ID = id
+				continue
+			}
+		}
+		if id, ok := exprIDs[r["expression"].(string)]; ok {
+			rules[i].ID = id
+		}
+	}
+
+	return rules
+}
+
+func expandCloudflareRulesetLogging(m map[string]interface{}) *cloudflare.RulesetRuleLogging {
+	enabled := m["enabled"].(bool)
+	return &cloudflare.RulesetRuleLogging{Enabled: &enabled}
+}
+
+func expandCloudflareRulesetActionParameters(m map[string]interface{}) *cloudflare.RulesetRuleActionParameters {
+	return &cloudflare.RulesetRuleActionParameters{
+		ID:          m["id"].(string),
+		Overrides:   expandStringMap(m["overrides"].(map[string]interface{})),
+		URI:         expandStringMap(m["uri"].(map[string]interface{})),
+		Headers:     expandStringMap(m["headers"].(map[string]interface{})),
+		MatchedData: expandStringMap(m["matched_data"].(map[string]interface{})),
+	}
+}
+
+func expandStringMap(in map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v.(string)
+	}
+
+	return out
+}
+
+func expandCloudflareRulesetRateLimit(m map[string]interface{}) *cloudflare.RulesetRuleRateLimit {
+	characteristics := make([]string, 0)
+	for _, c := range m["characteristics"].([]interface{}) {
+		characteristics = append(characteristics, c.(string))
+	}
+
+	return &cloudflare.RulesetRuleRateLimit{
+		Characteristics:   characteristics,
+		Period:            m["period"].(int),
+		RequestsPerPeriod: m["requests_per_period"].(int),
+		MitigationTimeout: m["mitigation_timeout"].(int),
+	}
+}
+
+func flattenCloudflareRulesetRules(rules []cloudflare.RulesetRule) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		m := map[string]interface{}{
+			"id":          rule.ID,
+			"ref":         rule.Ref,
+			"expression":  rule.Expression,
+			"action":      rule.Action,
+			"description": rule.Description,
+			"enabled":     rule.Enabled != nil && *rule.Enabled,
+		}
+
+		if rule.Logging != nil {
+			m["logging"] = []map[string]interface{}{flattenCloudflareRulesetLogging(rule.Logging)}
+		}
+
+		if rule.ActionParameters != nil {
+			m["action_parameters"] = []map[string]interface{}{flattenCloudflareRulesetActionParameters(rule.ActionParameters)}
+		}
+
+		if rule.RateLimit != nil {
+			m["ratelimit"] = []map[string]interface{}{flattenCloudflareRulesetRateLimit(rule.RateLimit)}
+		}
+
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func flattenCloudflareRulesetLogging(l *cloudflare.RulesetRuleLogging) map[string]interface{} {
+	return map[string]interface{}{
+		"enabled": l.Enabled != nil && *l.Enabled,
+	}
+}
+
+func flattenCloudflareRulesetActionParameters(ap *cloudflare.RulesetRuleActionParameters) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           ap.ID,
+		"overrides":    ap.Overrides,
+		"uri":          ap.URI,
+		"headers":      ap.Headers,
+		"matched_data": ap.MatchedData,
+	}
+}
+
+func flattenCloudflareRulesetRateLimit(rl *cloudflare.RulesetRuleRateLimit) map[string]interface{} {
+	return map[string]interface{}{
+		"characteristics":     rl.Characteristics,
+		"period":              rl.Period,
+		"requests_per_period": rl.RequestsPerPeriod,
+		"mitigation_timeout":  rl.MitigationTimeout,
+	}
+}